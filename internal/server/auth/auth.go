@@ -0,0 +1,121 @@
+// Package auth provides a pluggable authentication/authorization chain for
+// trivy-server, replacing the single shared-token model with OIDC, mTLS and
+// a principal-to-method policy layer suitable for multi-tenant deployments.
+package auth
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// Principal identifies the caller a request was authenticated as.
+type Principal struct {
+	// Subject is the OIDC "sub" claim, the mTLS certificate CN,
+	// "static-token" for the legacy shared-secret mode, or "anonymous" when
+	// no auth is configured at all.
+	Subject string
+	// Mode records which Authenticator produced this Principal, e.g.
+	// "oidc", "mtls" or "static-token".
+	Mode string
+}
+
+// Authenticator validates an inbound request and returns the Principal it
+// authenticated as, or an error if the request carries no valid credentials
+// for that authenticator.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Chain tries each Authenticator in order and returns the first successful
+// Principal. If none succeed, it returns the last error seen.
+type Chain struct {
+	authenticators []Authenticator
+	policy         *Policy
+
+	deprecationOnce sync.Once
+	staticTokenOnly bool
+	warn            func(msg string)
+}
+
+// NewChain builds a Chain. If staticTokenOnly is true (static-token auth is
+// configured and nothing else), a deprecation warning is logged once via warn
+// the first time the chain authenticates a request.
+func NewChain(policy *Policy, staticTokenOnly bool, warn func(msg string), authenticators ...Authenticator) *Chain {
+	return &Chain{
+		authenticators:  authenticators,
+		policy:          policy,
+		staticTokenOnly: staticTokenOnly,
+		warn:            warn,
+	}
+}
+
+// Authenticate runs the chain and, if a policy is configured, checks that
+// the resulting Principal is allowed to call method.
+func (c *Chain) Authenticate(r *http.Request, method string) (Principal, error) {
+	if c.staticTokenOnly && c.warn != nil {
+		c.deprecationOnce.Do(func() {
+			c.warn("static shared-token auth is deprecated; configure --oidc-issuer or mTLS instead")
+		})
+	}
+
+	var lastErr error
+	for _, a := range c.authenticators {
+		p, err := a.Authenticate(r)
+		if err == nil {
+			if c.policy != nil && !c.policy.Allowed(p, method) {
+				return Principal{}, xerrors.Errorf("principal %q is not allowed to call %s", p.Subject, method)
+			}
+			return p, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = xerrors.New("no authenticator configured")
+	}
+	return Principal{}, xerrors.Errorf("authentication failed: %w", lastErr)
+}
+
+// PrincipalFromTLS extracts a Principal from a verified client certificate's
+// Subject Common Name, for mTLS mode.
+func PrincipalFromTLS(state *tls.ConnectionState) (Principal, error) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return Principal{}, xerrors.New("no client certificate presented")
+	}
+	cert := state.PeerCertificates[0]
+	if cert.Subject.CommonName == "" {
+		return Principal{}, xerrors.New("client certificate has no Common Name")
+	}
+	return Principal{Subject: cert.Subject.CommonName, Mode: "mtls"}, nil
+}
+
+// MTLSAuthenticator authenticates requests using the CN of the verified
+// client certificate presented during the TLS handshake.
+type MTLSAuthenticator struct{}
+
+func (MTLSAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	return PrincipalFromTLS(r.TLS)
+}
+
+// StaticTokenAuthenticator retains the legacy shared-secret behavior of
+// withToken, for backward compatibility during migration to OIDC/mTLS. An
+// empty Token means static token auth isn't configured, which withToken
+// treated as open access rather than a hard failure; Authenticate preserves
+// that so a trivy-server started with no --token, --oidc-issuer or mTLS
+// keeps working in the long-supported no-auth deployment mode.
+type StaticTokenAuthenticator struct {
+	Token       string
+	TokenHeader string
+}
+
+func (a StaticTokenAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	if a.Token == "" {
+		return Principal{Subject: "anonymous", Mode: "none"}, nil
+	}
+	if r.Header.Get(a.TokenHeader) != a.Token {
+		return Principal{}, xerrors.New("invalid token")
+	}
+	return Principal{Subject: "static-token", Mode: "static-token"}, nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/xerrors"
+)
+
+// OIDCAuthenticator validates Bearer tokens against an OIDC issuer, with the
+// issuer's JWKS auto-refreshed by the underlying verifier.
+type OIDCAuthenticator struct {
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCAuthenticator discovers issuer's OIDC configuration (including its
+// JWKS endpoint) and returns an authenticator that validates tokens against
+// audience.
+func NewOIDCAuthenticator(ctx context.Context, issuer, audience string) (*OIDCAuthenticator, error) {
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to discover OIDC provider %s: %w", issuer, err)
+	}
+
+	verifier := provider.Verifier(&oidc.Config{ClientID: audience})
+	return &OIDCAuthenticator{verifier: verifier}, nil
+}
+
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return Principal{}, xerrors.New("missing bearer token")
+	}
+	rawToken := strings.TrimPrefix(header, "Bearer ")
+
+	idToken, err := a.verifier.Verify(r.Context(), rawToken)
+	if err != nil {
+		return Principal{}, xerrors.Errorf("failed to verify OIDC token: %w", err)
+	}
+
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return Principal{}, xerrors.Errorf("failed to parse OIDC claims: %w", err)
+	}
+	if claims.Subject == "" {
+		return Principal{}, xerrors.New("OIDC token has no subject claim")
+	}
+
+	return Principal{Subject: claims.Subject, Mode: "oidc"}, nil
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+
+	"golang.org/x/xerrors"
+)
+
+// Policy maps authenticated principals to the RPC methods they may call,
+// e.g. letting scanner clients call the Scanner/Cache services while
+// restricting a Prometheus scraper to /metrics.
+type Policy struct {
+	// Principals maps a principal Subject to a list of glob patterns
+	// (path.Match syntax) matched against the method string the server
+	// checks. Twirp handlers are checked once per service, not per method,
+	// against "Scanner.*", "Cache.*", "OSDetector.*" or "LibDetector.*";
+	// /metrics is checked against "metrics", /api/v1/db/refresh against
+	// "db-refresh", and gRPC calls against their full gRPC method name.
+	Principals map[string][]string `json:"principals"`
+	// DefaultAllow controls behavior for principals with no explicit entry.
+	DefaultAllow bool `json:"default_allow"`
+}
+
+// LoadPolicy reads a JSON policy file from path.
+func LoadPolicy(filePath string) (*Policy, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read policy file %s: %w", filePath, err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, xerrors.Errorf("failed to parse policy file %s: %w", filePath, err)
+	}
+	return &p, nil
+}
+
+// Allowed reports whether principal may call method.
+func (p *Policy) Allowed(principal Principal, method string) bool {
+	patterns, ok := p.Principals[principal.Subject]
+	if !ok {
+		return p.DefaultAllow
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, method); matched {
+			return true
+		}
+	}
+	return false
+}
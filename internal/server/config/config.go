@@ -1,21 +1,55 @@
 package config
 
 import (
+	"time"
+
 	"github.com/aquasecurity/trivy/internal/config"
 	"github.com/urfave/cli/v2"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/aquasecurity/trivy/internal/server/metrics"
+	"github.com/aquasecurity/trivy/pkg/log"
 )
 
 type Config struct {
 	config.GlobalConfig
 	config.DBConfig
 
-	Listen          string
-	Token           string
-	TokenHeader     string
-	MetricsRegistry *prometheus.Registry
-	GaugeMetric     *prometheus.GaugeVec
+	Listen           string
+	GRPCListen       string
+	Token            string
+	TokenHeader      string
+	LogFormat        string
+	LogLevel         string
+	DBUpdateInterval time.Duration
+	MetricsRegistry  *prometheus.Registry
+	GaugeMetric      *prometheus.GaugeVec
+	Metrics          *metrics.Metrics
+
+	CacheBackend   string
+	CacheTTL       time.Duration
+	CacheRedisAddr string
+	CacheRedisPass string
+	CacheRedisDB   int
+	CacheS3Bucket  string
+	CacheS3Prefix  string
+	CacheS3Region  string
+
+	OIDCIssuer     string
+	OIDCAudience   string
+	MTLSEnabled    bool
+	AuthPolicyFile string
+
+	NotificationWebhookURL    string
+	NotificationWebhookSecret string
+	NotificationFilePath      string
+
+	// VulnSrcFetchEnabled gates whether registered vulnsrc.Fetcher sources
+	// (e.g. alpine-secdb, debian-security-tracker) are fetched on each DB
+	// update. It defaults to false so tests and airgapped deployments don't
+	// silently make outbound HTTPS calls to third-party feeds.
+	VulnSrcFetchEnabled bool
 }
 
 func New(c *cli.Context) Config {
@@ -26,10 +60,34 @@ func New(c *cli.Context) Config {
 		GlobalConfig: gc,
 		DBConfig:     config.NewDBConfig(c),
 
-		Listen:          c.String("listen"),
-		Token:           c.String("token"),
-		TokenHeader:     c.String("token-header"),
-		MetricsRegistry: prometheus.NewRegistry(),
+		Listen:           c.String("listen"),
+		GRPCListen:       c.String("grpc-listen"),
+		Token:            c.String("token"),
+		TokenHeader:      c.String("token-header"),
+		LogFormat:        c.String("log-format"),
+		LogLevel:         c.String("log-level"),
+		DBUpdateInterval: c.Duration("db-update-interval"),
+		MetricsRegistry:  prometheus.NewRegistry(),
+
+		CacheBackend:   c.String("cache-backend"),
+		CacheTTL:       c.Duration("cache-ttl"),
+		CacheRedisAddr: c.String("cache-redis-addr"),
+		CacheRedisPass: c.String("cache-redis-password"),
+		CacheRedisDB:   c.Int("cache-redis-db"),
+		CacheS3Bucket:  c.String("cache-s3-bucket"),
+		CacheS3Prefix:  c.String("cache-s3-prefix"),
+		CacheS3Region:  c.String("cache-s3-region"),
+
+		OIDCIssuer:     c.String("oidc-issuer"),
+		OIDCAudience:   c.String("oidc-audience"),
+		MTLSEnabled:    c.Bool("mtls"),
+		AuthPolicyFile: c.String("auth-policy-file"),
+
+		NotificationWebhookURL:    c.String("notification-webhook-url"),
+		NotificationWebhookSecret: c.String("notification-webhook-secret"),
+		NotificationFilePath:      c.String("notification-file-path"),
+
+		VulnSrcFetchEnabled: c.Bool("vulnsrc-fetch-enabled"),
 	}
 }
 
@@ -37,6 +95,9 @@ func (c *Config) Init() (err error) {
 	if err := c.DBConfig.Init(); err != nil {
 		return err
 	}
+	if err := log.InitLoggerWithFormat(c.LogLevel == "debug", false, c.LogFormat); err != nil {
+		return err
+	}
 	if c.MetricsRegistry != nil {
 		c.GaugeMetric = prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -46,6 +107,7 @@ func (c *Config) Init() (err error) {
 			[]string{"action"},
 		)
 		c.MetricsRegistry.MustRegister(c.GaugeMetric)
+		c.Metrics = metrics.New(c.MetricsRegistry)
 	}
 	return nil
 }
@@ -3,12 +3,15 @@ package extendedConfig
 import (
 	"github.com/aquasecurity/trivy/internal/server/config"
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/aquasecurity/trivy/internal/server/metrics"
 )
 
 type ExtendedConfig struct {
 	Config          config.Config
 	MetricsRegistry *prometheus.Registry
 	GaugeMetric     *prometheus.GaugeVec
+	Metrics         *metrics.Metrics
 }
 
 func New(c config.Config) ExtendedConfig {
@@ -27,4 +30,5 @@ func (ec *ExtendedConfig) Init() {
 		[]string{"action"},
 	)
 	ec.MetricsRegistry.MustRegister(ec.GaugeMetric)
+	ec.Metrics = metrics.New(ec.MetricsRegistry)
 }
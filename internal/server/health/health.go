@@ -0,0 +1,96 @@
+// Package health implements a Clair-style aggregated health endpoint: every
+// subsystem that matters to serving correct results (the DB worker, each
+// registered vulnsrc.Fetcher, the on-disk cache) registers a Healthchecker,
+// and the aggregated handler reports 503 as soon as any essential subsystem
+// is unhealthy so a Kubernetes readiness probe can pull a stuck instance out
+// of rotation instead of letting it silently serve stale data.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Healthchecker reports the health of a single subsystem.
+type Healthchecker interface {
+	// Name identifies the subsystem, e.g. "db-worker" or "vulnsrc:alpine-secdb".
+	Name() string
+	// IsEssential reports whether this subsystem failing should fail the
+	// aggregated check. Non-essential subsystems are still reported, but
+	// never flip the overall status to unhealthy.
+	IsEssential() bool
+	IsHealthy() bool
+	// Message explains the current status, empty when healthy.
+	Message() string
+	LastUpdate() time.Time
+	LastSuccess() time.Time
+}
+
+var (
+	mu         sync.Mutex
+	registered []Healthchecker
+)
+
+// Register adds h to the set reported by Handler.
+func Register(h Healthchecker) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered = append(registered, h)
+}
+
+// List returns every registered Healthchecker.
+func List() []Healthchecker {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Healthchecker, len(registered))
+	copy(out, registered)
+	return out
+}
+
+type subsystemStatus struct {
+	Name        string    `json:"name"`
+	Essential   bool      `json:"essential"`
+	Healthy     bool      `json:"healthy"`
+	Message     string    `json:"message,omitempty"`
+	LastUpdate  time.Time `json:"last_update,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+}
+
+type aggregateStatus struct {
+	Healthy    bool              `json:"healthy"`
+	Subsystems []subsystemStatus `json:"subsystems"`
+}
+
+// Handler serves GET /health: HTTP 200 only if every essential registered
+// subsystem is healthy, 503 otherwise, with the full per-subsystem detail
+// as the JSON body.
+func Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		agg := aggregateStatus{Healthy: true}
+		for _, h := range List() {
+			healthy := h.IsHealthy()
+			agg.Subsystems = append(agg.Subsystems, subsystemStatus{
+				Name:        h.Name(),
+				Essential:   h.IsEssential(),
+				Healthy:     healthy,
+				Message:     h.Message(),
+				LastUpdate:  h.LastUpdate(),
+				LastSuccess: h.LastSuccess(),
+			})
+			if h.IsEssential() && !healthy {
+				agg.Healthy = false
+			}
+		}
+
+		code := http.StatusOK
+		if !agg.Healthy {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(agg)
+	}
+}
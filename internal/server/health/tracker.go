@@ -0,0 +1,119 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// Tracker is a reusable Healthchecker for subsystems whose health is
+// defined by "has this kept failing for longer than a grace window since
+// its last success" - the db-worker update loop and each vulnsrc.Fetcher
+// both fit this shape.
+type Tracker struct {
+	name      string
+	essential bool
+	grace     time.Duration
+
+	mu          sync.Mutex
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastErr     error
+}
+
+// NewTracker returns a Tracker that turns unhealthy once its latest attempt
+// has failed and grace has elapsed since its last success. It starts in the
+// healthy state, with the last success set to now, so a subsystem that
+// hasn't run yet doesn't immediately fail the aggregated check.
+func NewTracker(name string, essential bool, grace time.Duration) *Tracker {
+	return &Tracker{
+		name:        name,
+		essential:   essential,
+		grace:       grace,
+		lastSuccess: time.Now(),
+	}
+}
+
+// Attempt records that a new update cycle started. A nil Tracker is a no-op,
+// so callers that don't have one configured (e.g. in tests) can call it
+// unconditionally.
+func (t *Tracker) Attempt() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastAttempt = time.Now()
+}
+
+// Succeed records a successful update cycle.
+func (t *Tracker) Succeed() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastAttempt = time.Now()
+	t.lastSuccess = t.lastAttempt
+	t.lastErr = nil
+}
+
+// Fail records a failed update cycle.
+func (t *Tracker) Fail(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastAttempt = time.Now()
+	t.lastErr = err
+}
+
+func (t *Tracker) Name() string {
+	if t == nil {
+		return ""
+	}
+	return t.name
+}
+
+func (t *Tracker) IsEssential() bool {
+	return t != nil && t.essential
+}
+
+func (t *Tracker) IsHealthy() bool {
+	if t == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastErr == nil || time.Since(t.lastSuccess) < t.grace
+}
+
+func (t *Tracker) Message() string {
+	if t == nil {
+		return ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lastErr != nil {
+		return t.lastErr.Error()
+	}
+	return ""
+}
+
+func (t *Tracker) LastUpdate() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastAttempt
+}
+
+func (t *Tracker) LastSuccess() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastSuccess
+}
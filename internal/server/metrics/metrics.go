@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Metrics bundles every Prometheus collector exposed by trivy-server beyond
+// the legacy `trivy` GaugeVec, following the labeling conventions used by
+// Prometheus/Cortex/Loki for their own RPC servers.
+type Metrics struct {
+	RPCRequestsTotal   *prometheus.CounterVec
+	RPCRequestDuration *prometheus.HistogramVec
+	InFlightRequests   prometheus.Gauge
+
+	DBUpdateDuration      prometheus.Histogram
+	DBUpdateFailuresTotal prometheus.Counter
+	DBUpdateNextScheduled prometheus.Gauge
+	DBUpdateBackoff       prometheus.Gauge
+
+	VulnerabilitiesDetectedTotal *prometheus.CounterVec
+	CacheOperationsTotal         *prometheus.CounterVec
+	CacheBytesStored             prometheus.Gauge
+	AuthRequestsTotal            *prometheus.CounterVec
+	VulnSourceUpdateTimestamp    *prometheus.GaugeVec
+}
+
+// New creates the collectors and registers them, along with the Go runtime
+// and process collectors, on registry.
+func New(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		RPCRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "trivy",
+			Name:      "rpc_requests_total",
+			Help:      "Total number of RPC requests handled, labeled by service, method and status code.",
+		}, []string{"service", "method", "code"}),
+
+		RPCRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "trivy",
+			Name:      "rpc_request_duration_seconds",
+			Help:      "RPC request latency in seconds, labeled by service, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "method", "code"}),
+
+		InFlightRequests: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "trivy",
+			Name:      "in_flight_requests",
+			Help:      "Number of RPC requests currently being served.",
+		}),
+
+		DBUpdateDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "trivy",
+			Name:      "db_update_duration_seconds",
+			Help:      "Time taken to download and hot-swap the vulnerability DB.",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600},
+		}),
+
+		DBUpdateFailuresTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "trivy",
+			Name:      "db_update_failures_total",
+			Help:      "Total number of failed vulnerability DB update attempts.",
+		}),
+
+		DBUpdateNextScheduled: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "trivy",
+			Name:      "db_update_next_scheduled_timestamp_seconds",
+			Help:      "Unix timestamp at which the next scheduled DB update is expected to run.",
+		}),
+
+		DBUpdateBackoff: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "trivy",
+			Name:      "db_update_backoff_seconds",
+			Help:      "Current backoff duration applied to the DB update schedule after failures.",
+		}),
+
+		VulnerabilitiesDetectedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "trivy",
+			Name:      "vulnerabilities_detected_total",
+			Help:      "Total number of vulnerabilities detected, labeled by severity, OS family and package source.",
+		}, []string{"severity", "os", "pkg_source"}),
+
+		CacheOperationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "trivy",
+			Name:      "cache_operations_total",
+			Help:      "Total number of cache operations, labeled by operation and result.",
+		}, []string{"op", "result"}),
+
+		CacheBytesStored: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "trivy",
+			Name:      "cache_bytes_stored",
+			Help:      "Approximate number of bytes currently held by the cache backend.",
+		}),
+
+		AuthRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "trivy",
+			Name:      "auth_requests_total",
+			Help:      "Total number of authentication attempts, labeled by result and RPC method.",
+		}, []string{"result", "method"}),
+
+		VulnSourceUpdateTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "trivy",
+			Name:      "vulnsrc_update_timestamp_seconds",
+			Help:      "Unix timestamp of the last update attempt/success per registered vulnerability source.",
+		}, []string{"source", "action"}),
+	}
+
+	registry.MustRegister(
+		m.RPCRequestsTotal,
+		m.RPCRequestDuration,
+		m.InFlightRequests,
+		m.DBUpdateDuration,
+		m.DBUpdateFailuresTotal,
+		m.DBUpdateNextScheduled,
+		m.DBUpdateBackoff,
+		m.VulnerabilitiesDetectedTotal,
+		m.CacheOperationsTotal,
+		m.CacheBytesStored,
+		m.AuthRequestsTotal,
+		m.VulnSourceUpdateTimestamp,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// ObserveRPC records a single completed RPC call.
+func (m *Metrics) ObserveRPC(service, method, code string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.RPCRequestsTotal.WithLabelValues(service, method, code).Inc()
+	m.RPCRequestDuration.WithLabelValues(service, method, code).Observe(duration.Seconds())
+}
+
+// ObserveDBUpdate records the outcome and duration of a DB update attempt.
+func (m *Metrics) ObserveDBUpdate(duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.DBUpdateDuration.Observe(duration.Seconds())
+	if err != nil {
+		m.DBUpdateFailuresTotal.Inc()
+	}
+}
+
+// ObserveCacheOp records a single cache backend operation, e.g.
+// ObserveCacheOp("get", "hit") or ObserveCacheOp("put", "ok").
+func (m *Metrics) ObserveCacheOp(op, result string) {
+	if m == nil {
+		return
+	}
+	m.CacheOperationsTotal.WithLabelValues(op, result).Inc()
+}
+
+// ObserveAuth records the outcome of a single authentication attempt.
+func (m *Metrics) ObserveAuth(result, method string) {
+	if m == nil {
+		return
+	}
+	m.AuthRequestsTotal.WithLabelValues(result, method).Inc()
+}
+
+// ObserveVulnSource records a timestamp for a vulnsrc.Fetcher's "attempt" or
+// "success" action, analogous to the legacy last_db_update(_attempt) gauge
+// but keyed per registered source.
+func (m *Metrics) ObserveVulnSource(source, action string) {
+	if m == nil {
+		return
+	}
+	m.VulnSourceUpdateTimestamp.WithLabelValues(source, action).Set(float64(time.Now().Unix()))
+}
+
+// ObserveVuln records a single vulnerability found by a scan. It's meant to
+// be called once per types.DetectedVulnerability returned from a scan/detect
+// RPC, labeled by severity, OS family and package source (os/lang-specific).
+//
+// NOT YET WIRED: this snapshot of the tree doesn't contain the scanner
+// server implementation that produces DetectedVulnerability results
+// (initializeScanServer in pkg/rpc/server is referenced but not defined
+// here), so there is no real call site to invoke this from yet. Wire it in
+// wherever that scan path lands.
+func (m *Metrics) ObserveVuln(severity, os, pkgSource string) {
+	if m == nil {
+		return
+	}
+	m.VulnerabilitiesDetectedTotal.WithLabelValues(severity, os, pkgSource).Inc()
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/xerrors"
+)
+
+// redisBackend stores blob info as plain Redis values, relying on Redis's
+// own TTL/eviction policy (maxmemory-policy) for cache sizing.
+type redisBackend struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func newRedisBackend(opts Options) (Backend, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     opts.RedisAddr,
+		Password: opts.RedisPassword,
+		DB:       opts.RedisDB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, xerrors.Errorf("failed to connect to redis at %s: %w", opts.RedisAddr, err)
+	}
+
+	return &redisBackend{client: client, ttl: opts.TTL}, nil
+}
+
+func (b *redisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := b.client.Get(ctx, key).Bytes()
+	switch {
+	case err == redis.Nil:
+		return nil, false, nil
+	case err != nil:
+		return nil, false, xerrors.Errorf("failed to get %s from redis: %w", key, err)
+	}
+	return value, true, nil
+}
+
+func (b *redisBackend) Put(ctx context.Context, key string, value []byte) error {
+	if err := b.client.Set(ctx, key, value, b.ttl).Err(); err != nil {
+		return xerrors.Errorf("failed to put %s into redis: %w", key, err)
+	}
+	return nil
+}
+
+func (b *redisBackend) Delete(ctx context.Context, key string) error {
+	if err := b.client.Del(ctx, key).Err(); err != nil {
+		return xerrors.Errorf("failed to delete %s from redis: %w", key, err)
+	}
+	return nil
+}
+
+func (b *redisBackend) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/xerrors"
+)
+
+// s3Backend stores blob info as objects under a shared bucket/prefix and
+// runs a background sweep to evict objects older than ttl, since S3 itself
+// has no per-object TTL outside of bucket-wide lifecycle rules.
+type s3Backend struct {
+	client *s3.Client
+	bucket string
+	prefix string
+	ttl    time.Duration
+
+	stop chan struct{}
+}
+
+func newS3Backend(opts Options) (Backend, error) {
+	if opts.S3Bucket == "" {
+		return nil, xerrors.New("--cache-s3-bucket is required for the s3 cache backend")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(opts.S3Region))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load AWS config: %w", err)
+	}
+
+	b := &s3Backend{
+		client: s3.NewFromConfig(cfg),
+		bucket: opts.S3Bucket,
+		prefix: opts.S3Prefix,
+		ttl:    opts.TTL,
+		stop:   make(chan struct{}),
+	}
+
+	if b.ttl > 0 {
+		go b.evictLoop()
+	}
+
+	return b, nil
+}
+
+func (b *s3Backend) objectKey(key string) string {
+	return path.Join(b.prefix, key)
+}
+
+func (b *s3Backend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	out, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.objectKey(key)),
+	})
+	var noSuchKey *types.NoSuchKey
+	switch {
+	case errors.As(err, &noSuchKey):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, xerrors.Errorf("failed to get %s from s3: %w", key, err)
+	}
+	defer out.Body.Close()
+
+	value, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to read s3 object body for %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+func (b *s3Backend) Put(ctx context.Context, key string, value []byte) error {
+	_, err := b.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.objectKey(key)),
+		Body:   bytes.NewReader(value),
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to put %s into s3: %w", key, err)
+	}
+	return nil
+}
+
+func (b *s3Backend) Delete(ctx context.Context, key string) error {
+	_, err := b.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.objectKey(key)),
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to delete %s from s3: %w", key, err)
+	}
+	return nil
+}
+
+func (b *s3Backend) Close() error {
+	close(b.stop)
+	return nil
+}
+
+// evictLoop periodically deletes objects under prefix whose LastModified is
+// older than ttl.
+func (b *s3Backend) evictLoop() {
+	ticker := time.NewTicker(b.ttl / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.evictExpired()
+		}
+	}
+}
+
+func (b *s3Backend) evictExpired() {
+	ctx := context.Background()
+	paginator := s3.NewListObjectsV2Paginator(b.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.bucket),
+		Prefix: aws.String(b.prefix),
+	})
+
+	cutoff := time.Now().Add(-b.ttl)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return
+		}
+		for _, obj := range page.Contents {
+			if obj.LastModified != nil && obj.LastModified.Before(cutoff) {
+				_, _ = b.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+					Bucket: aws.String(b.bucket),
+					Key:    obj.Key,
+				})
+			}
+		}
+	}
+}
@@ -2,20 +2,35 @@ package log
 
 import (
 	"os"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/xerrors"
 )
 
+// dedupeWindow is how long an identical error-level (level, message) pair
+// is suppressed for after it's logged, so a noisy repeated failure (e.g. a
+// DB update erroring every retry) doesn't flood the output.
+const dedupeWindow = 10 * time.Second
+
 var (
 	Logger      *zap.SugaredLogger
 	debugOption bool
 )
 
 func InitLogger(debug, disable bool) (err error) {
+	return InitLoggerWithFormat(debug, disable, "")
+}
+
+// InitLoggerWithFormat is InitLogger plus a format argument selecting the
+// log encoder: "json" for machine-parseable logs suitable for shipping to
+// an aggregator (Loki/ELK/Datadog), or "console" (also the default for "")
+// for the existing human-readable, colorized format.
+func InitLoggerWithFormat(debug, disable bool, format string) (err error) {
 	debugOption = debug
-	Logger, err = NewLogger(debug, disable)
+	Logger, err = NewLogger(debug, disable, format)
 	if err != nil {
 		return xerrors.Errorf("error in new logger: %w", err)
 	}
@@ -23,7 +38,7 @@ func InitLogger(debug, disable bool) (err error) {
 
 }
 
-func NewLogger(debug, disable bool) (*zap.SugaredLogger, error) {
+func NewLogger(debug, disable bool, format string) (*zap.SugaredLogger, error) {
 	// First, define our level-handling logic.
 	errorPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 		return lvl >= zapcore.ErrorLevel
@@ -49,7 +64,14 @@ func NewLogger(debug, disable bool) (*zap.SugaredLogger, error) {
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
+	var encoder zapcore.Encoder
+	if format == "json" {
+		jsonEncoderConfig := encoderConfig
+		jsonEncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewJSONEncoder(jsonEncoderConfig)
+	} else {
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
 
 	// High-priority output should also go to standard error, and low-priority
 	// output should also go to standard out.
@@ -64,10 +86,10 @@ func NewLogger(debug, disable bool) (*zap.SugaredLogger, error) {
 		consoleLogs = zapcore.Lock(devNull)
 	}
 
-	core := zapcore.NewTee(
-		zapcore.NewCore(consoleEncoder, consoleErrors, errorPriority),
-		zapcore.NewCore(consoleEncoder, consoleLogs, logPriority),
-	)
+	core := newDedupeCore(zapcore.NewTee(
+		zapcore.NewCore(encoder, consoleErrors, errorPriority),
+		zapcore.NewCore(encoder, consoleLogs, logPriority),
+	), dedupeWindow)
 
 	opts := []zap.Option{zap.ErrorOutput(zapcore.Lock(os.Stderr))}
 	if debug {
@@ -84,3 +106,55 @@ func Fatal(err error) {
 	}
 	Logger.Fatal(err)
 }
+
+// newDedupeCore wraps core so that identical (level, message) pairs at
+// error level or above are suppressed if seen again within window. Entries
+// below error level always pass through, so per-request info lines are
+// never dropped.
+func newDedupeCore(core zapcore.Core, window time.Duration) zapcore.Core {
+	return &dedupeCore{Core: core, window: window, seen: &sync.Map{}}
+}
+
+type dedupeCore struct {
+	zapcore.Core
+	window time.Duration
+	seen   *sync.Map // key (level|message) -> time.Time of last emission
+}
+
+func (c *dedupeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupeCore{Core: c.Core.With(fields), window: c.window, seen: c.seen}
+}
+
+func (c *dedupeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *dedupeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < zapcore.ErrorLevel {
+		return c.Core.Write(ent, fields)
+	}
+	key := ent.Level.String() + "|" + ent.Message
+
+	now := time.Now()
+	if last, ok := c.seen.Load(key); ok && now.Sub(last.(time.Time)) < c.window {
+		return nil
+	}
+	c.seen.Store(key, now)
+
+	return c.Core.Write(ent, fields)
+}
+
+// With returns a child of Logger carrying the given structured fields, for
+// callers that want values like source, app_version, db_version or
+// duration_ms to stay queryable in an aggregator instead of being
+// interpolated into the message string.
+func With(fields ...zap.Field) *zap.SugaredLogger {
+	args := make([]interface{}, len(fields))
+	for i, f := range fields {
+		args[i] = f
+	}
+	return Logger.With(args...)
+}
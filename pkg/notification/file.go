@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// FileNotifier appends each Notification as a single JSON line to Path,
+// giving operators a push-based audit trail without standing up a webhook
+// receiver.
+type FileNotifier struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileNotifier returns a FileNotifier appending to path.
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{Path: path}
+}
+
+func (f *FileNotifier) Name() string {
+	return "file"
+}
+
+func (f *FileNotifier) Send(_ context.Context, n Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return xerrors.Errorf("failed to open notification file %s: %w", f.Path, err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(n); err != nil {
+		return xerrors.Errorf("failed to write notification to %s: %w", f.Path, err)
+	}
+	return nil
+}
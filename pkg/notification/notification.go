@@ -0,0 +1,94 @@
+// Package notification lets downstream systems learn about DB updates
+// as they happen instead of polling /metrics or /health, borrowing from
+// Clair's ext/notification extension point: a Notifier registers itself
+// under a name, and every configured Notifier is sent a Notification after
+// each successful dbWorker update.
+//
+// PARTIALLY IMPLEMENTED: New/Removed/Changed only ever carry real content
+// for the per-source notifications dbWorker.updateVulnSources broadcasts,
+// since vulnsrc.LoadLocalStore/Diff can compare a fetcher's fresh result
+// against its own previously written snapshot. The notification sent after
+// the bundled trivy-db artifact itself updates (dbWorker.notifyUpdate)
+// still ships empty slices: trivy-db exposes no API to read back a prior
+// snapshot's contents through the client used there, only the current one.
+// See Notification's field comments for detail.
+package notification
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Notification describes a single completed DB update.
+type Notification struct {
+	// New, Removed and Changed are populated for the per-source
+	// notifications broadcast from dbWorker.updateVulnSources, diffed via
+	// vulnsrc.Diff against that source's previous vulnsrc.LoadLocalStore
+	// snapshot. They're left empty for the notification broadcast after
+	// the bundled trivy-db artifact updates (dbWorker.notifyUpdate), since
+	// trivy-db exposes no API to read back a prior snapshot's contents
+	// through the client used there, only the current one.
+	New     []types.DetectedVulnerability `json:"new,omitempty"`
+	Removed []types.DetectedVulnerability `json:"removed,omitempty"`
+	Changed []types.DetectedVulnerability `json:"changed,omitempty"`
+
+	// DBVersion identifies what this notification was built from: the
+	// trivy-db update timestamp (RFC3339) for dbWorker.notifyUpdate, or
+	// the vulnsrc.Fetcher's source name for a per-source notification,
+	// since neither exposes a queryable content/schema version through
+	// the APIs available here.
+	DBVersion string    `json:"db_version"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier delivers a Notification to some downstream system.
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+var (
+	mu        sync.Mutex
+	notifiers = map[string]Notifier{}
+)
+
+// RegisterNotifier adds n to the registry under name. It panics if name is
+// already registered, following the same fail-fast convention as
+// vulnsrc.RegisterFetcher.
+func RegisterNotifier(name string, n Notifier) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := notifiers[name]; exists {
+		panic("notification: RegisterNotifier called twice for " + name)
+	}
+	notifiers[name] = n
+}
+
+// ListNotifiers returns every registered Notifier, in no particular order.
+func ListNotifiers() []Notifier {
+	mu.Lock()
+	defer mu.Unlock()
+
+	list := make([]Notifier, 0, len(notifiers))
+	for _, n := range notifiers {
+		list = append(list, n)
+	}
+	return list
+}
+
+// Broadcast sends n to every registered Notifier. A single Notifier's
+// failure is logged but never blocks or fails delivery to the others.
+func Broadcast(ctx context.Context, n Notification) {
+	for _, notifier := range ListNotifiers() {
+		if err := notifier.Send(ctx, n); err != nil {
+			log.With(zap.String("notifier", notifier.Name()), zap.Error(err)).Warn("notification delivery failed")
+		}
+	}
+}
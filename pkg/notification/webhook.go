@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// WebhookNotifier POSTs the Notification as JSON to a configured URL,
+// retrying with exponential backoff and, when Secret is set, signing the
+// body with HMAC-SHA256 so the receiver can verify authenticity.
+type WebhookNotifier struct {
+	URL        string
+	Secret     string
+	MaxRetries int
+	Client     *http.Client
+}
+
+// NewWebhookNotifier returns a WebhookNotifier with sane retry/timeout
+// defaults.
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:        url,
+		Secret:     secret,
+		MaxRetries: 3,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (w *WebhookNotifier) Send(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal notification: %w", err)
+	}
+
+	backoff := time.Second
+	var lastErr error
+	for attempt := 0; attempt <= w.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if err := w.post(ctx, body); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+
+	return xerrors.Errorf("webhook notifier: giving up after %d attempts: %w", w.MaxRetries+1, lastErr)
+}
+
+func (w *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Trivy-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return xerrors.Errorf("webhook receiver returned status %d", resp.StatusCode)
+	}
+	return nil
+}
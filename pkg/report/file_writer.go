@@ -0,0 +1,72 @@
+package report
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// WriteResultsToFile renders results in the given format (see WriteResults)
+// and atomically publishes them to path, so concurrent `trivy` invocations
+// sharing an --output path (e.g. CI matrix jobs on a shared volume) can't
+// truncate one another's report: the render is written to a temp file in
+// path's directory first, then linked into place. If path already exists
+// (for example, written by a run that raced this one), it falls back to
+// "<base>-<unix-nano>-<n><ext>" with an incrementing n, analogous to
+// JFrog's getUniqueErrorOrDelayFilePath.
+func WriteResultsToFile(format, path string, results Results, templatePath string, light bool) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := ioutil.TempFile(dir, ".trivy-report-*.tmp")
+	if err != nil {
+		return xerrors.Errorf("failed to create temp report file in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // once published, this drops only the temp name; the report stays under its linked name
+
+	if err := WriteResults(format, tmp, results, templatePath, light); err != nil {
+		tmp.Close()
+		return xerrors.Errorf("failed to render report: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return xerrors.Errorf("failed to close temp report file: %w", err)
+	}
+
+	if _, err := publish(tmpPath, path); err != nil {
+		return xerrors.Errorf("failed to publish report to %s: %w", path, err)
+	}
+	return nil
+}
+
+// publish links tmpPath into place at path. os.Link fails with an
+// already-exists error instead of silently overwriting like os.Rename
+// would, so a genuine collision is detected rather than masked; publish
+// then retries against "<base>-<unix-nano>-<n><ext>" candidates, bumping n
+// until a free name is claimed.
+func publish(tmpPath, path string) (string, error) {
+	if err := os.Link(tmpPath, path); err == nil {
+		return path, nil
+	} else if !os.IsExist(err) {
+		return "", err
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	ts := time.Now().UnixNano()
+
+	for n := 1; ; n++ {
+		candidate := fmt.Sprintf("%s-%d-%d%s", base, ts, n, ext)
+		err := os.Link(tmpPath, candidate)
+		if err == nil {
+			return candidate, nil
+		}
+		if !os.IsExist(err) {
+			return "", err
+		}
+	}
+}
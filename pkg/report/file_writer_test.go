@@ -0,0 +1,56 @@
+package report_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/report"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWriteResultsToFile_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trivy-report.json")
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results := report.Results{
+				{
+					Target: fmt.Sprintf("target-%d", i),
+					Vulnerabilities: []types.DetectedVulnerability{
+						{VulnerabilityID: fmt.Sprintf("CVE-%d", i)},
+					},
+				},
+			}
+			errs[i] = report.WriteResultsToFile("json", path, results, "", false)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		assert.NoError(t, err, "goroutine %d", i)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "trivy-report*.json"))
+	assert.NoError(t, err)
+	assert.Len(t, matches, n, "expected one intact file per goroutine")
+
+	for _, m := range matches {
+		data, err := ioutil.ReadFile(m)
+		assert.NoError(t, err, m)
+
+		var parsed report.Results
+		assert.NoError(t, json.Unmarshal(data, &parsed), "file %s should be valid json", m)
+	}
+}
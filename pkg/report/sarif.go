@@ -0,0 +1,178 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const sarifSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// SarifWriter renders Results as SARIF 2.1.0 JSON, one run per Target, so
+// they can be uploaded to GitHub code scanning, Azure DevOps and other
+// SARIF consumers without hand-rolling a Go template.
+type SarifWriter struct {
+	Output io.Writer
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	HelpURI          string    `json:"helpUri"`
+	Help             sarifText `json:"help"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                `json:"ruleId"`
+	Level      string                `json:"level"`
+	Message    sarifText             `json:"message"`
+	Locations  []sarifLocation       `json:"locations"`
+	Properties sarifResultProperties `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifResultProperties struct {
+	PkgName          string `json:"pkgName,omitempty"`
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	FixedVersion     string `json:"fixedVersion,omitempty"`
+}
+
+func (sw SarifWriter) Write(results Results) error {
+	sarifLog := sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+	}
+
+	for _, result := range results {
+		sarifLog.Runs = append(sarifLog.Runs, toSarifRun(result))
+	}
+
+	output, err := json.MarshalIndent(sarifLog, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to marshal sarif: %w", err)
+	}
+
+	if _, err := sw.Output.Write(output); err != nil {
+		return xerrors.Errorf("failed to write sarif: %w", err)
+	}
+	return nil
+}
+
+func toSarifRun(result Result) sarifRun {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "Trivy",
+				InformationURI: "https://github.com/aquasecurity/trivy",
+			},
+		},
+	}
+
+	seenRules := map[string]bool{}
+	for _, v := range result.Vulnerabilities {
+		if !seenRules[v.VulnerabilityID] {
+			seenRules[v.VulnerabilityID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, toSarifRule(v))
+		}
+		run.Results = append(run.Results, toSarifResult(v, result.Target))
+	}
+
+	return run
+}
+
+func toSarifRule(v types.DetectedVulnerability) sarifRule {
+	return sarifRule{
+		ID:               v.VulnerabilityID,
+		Name:             v.VulnerabilityID,
+		HelpURI:          sarifHelpURI(v.VulnerabilityID),
+		ShortDescription: sarifText{Text: sarifTitle(v)},
+		Help:             sarifText{Text: v.Description},
+	}
+}
+
+func toSarifResult(v types.DetectedVulnerability, target string) sarifResult {
+	return sarifResult{
+		RuleID:  v.VulnerabilityID,
+		Level:   sarifLevel(v.Severity),
+		Message: sarifText{Text: sarifTitle(v)},
+		Locations: []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: target},
+				},
+			},
+		},
+		Properties: sarifResultProperties{
+			PkgName:          v.PkgName,
+			InstalledVersion: v.InstalledVersion,
+			FixedVersion:     v.FixedVersion,
+		},
+	}
+}
+
+func sarifTitle(v types.DetectedVulnerability) string {
+	if v.Title != "" {
+		return v.Title
+	}
+	return v.Description
+}
+
+// sarifLevel maps Trivy severities to the SARIF result.level vocabulary:
+// CRITICAL/HIGH are build-breaking errors, MEDIUM is a warning, everything
+// else (LOW/UNKNOWN) is an informational note.
+func sarifLevel(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL", "HIGH":
+		return "error"
+	case "MEDIUM":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func sarifHelpURI(vulnID string) string {
+	return fmt.Sprintf("https://avd.aquasec.com/nvd/%s", strings.ToLower(vulnID))
+}
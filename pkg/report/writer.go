@@ -0,0 +1,152 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/template"
+
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Result holds the vulnerabilities found for a single scan target.
+type Result struct {
+	Target          string                        `json:"Target"`
+	Type            string                        `json:"Type,omitempty"`
+	Vulnerabilities []types.DetectedVulnerability `json:"Vulnerabilities"`
+}
+
+// Results is the top-level output of a scan, one Result per target.
+type Results []Result
+
+// Writer serializes Results in a single output format.
+type Writer interface {
+	Write(Results) error
+}
+
+// WriteResults writes results to output in the given format: "table",
+// "json", "template" or "sarif". templatePath is only consulted for
+// "template": a leading "@" treats the rest as a file path to read, any
+// other value is used as the template text directly.
+func WriteResults(format string, output io.Writer, results Results, templatePath string, light bool) error {
+	var writer Writer
+	switch format {
+	case "table":
+		writer = &TableWriter{Output: output, Light: light}
+	case "json":
+		writer = &JsonWriter{Output: output}
+	case "template":
+		tmpl, err := loadTemplate(templatePath)
+		if err != nil {
+			return xerrors.Errorf("failed to load template: %w", err)
+		}
+		writer = &TemplateWriter{Output: output, Template: tmpl}
+	case "sarif":
+		writer = &SarifWriter{Output: output}
+	default:
+		return xerrors.Errorf("unknown format: %v", format)
+	}
+
+	if err := writer.Write(results); err != nil {
+		return xerrors.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
+
+// TableWriter renders each Result as a human-readable ASCII table. Light
+// drops the Title column for a more compact, CI-log-friendly view.
+type TableWriter struct {
+	Output io.Writer
+	Light  bool
+}
+
+func (tw TableWriter) Write(results Results) error {
+	for _, result := range results {
+		tw.write(result)
+	}
+	return nil
+}
+
+func (tw TableWriter) write(result Result) {
+	if len(result.Vulnerabilities) == 0 {
+		return
+	}
+
+	header := []string{"Library", "Vulnerability ID", "Severity", "Installed Version", "Fixed Version"}
+	if !tw.Light {
+		header = append(header, "Title")
+	}
+
+	table := tablewriter.NewWriter(tw.Output)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(true)
+
+	for _, v := range result.Vulnerabilities {
+		title := v.Title
+		if title == "" {
+			title = v.Description
+		}
+		if len(title) > 23 {
+			title = title[:23] + "..."
+		}
+
+		row := []string{v.PkgName, v.VulnerabilityID, v.Severity, v.InstalledVersion, v.FixedVersion}
+		if !tw.Light {
+			row = append(row, title)
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+}
+
+// JsonWriter writes Results as indented JSON.
+type JsonWriter struct {
+	Output io.Writer
+}
+
+func (jw JsonWriter) Write(results Results) error {
+	output, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to marshal json: %w", err)
+	}
+
+	if _, err := jw.Output.Write(output); err != nil {
+		return xerrors.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+// TemplateWriter executes a user-supplied Go template against Results.
+type TemplateWriter struct {
+	Output   io.Writer
+	Template *template.Template
+}
+
+func (tw TemplateWriter) Write(results Results) error {
+	if err := tw.Template.Execute(tw.Output, results); err != nil {
+		return xerrors.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+func loadTemplate(templatePath string) (*template.Template, error) {
+	templateStr := templatePath
+	if strings.HasPrefix(templatePath, "@") {
+		buf, err := ioutil.ReadFile(strings.TrimPrefix(templatePath, "@"))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read template %s: %w", templatePath, err)
+		}
+		templateStr = string(buf)
+	}
+
+	tmpl, err := template.New("output template").Parse(templateStr)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
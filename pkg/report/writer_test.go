@@ -196,6 +196,65 @@ func TestReportWriter_JSON(t *testing.T) {
 
 }
 
+func TestReportWriter_SARIF(t *testing.T) {
+	testCases := []struct {
+		name          string
+		detectedVulns []types.DetectedVulnerability
+		wantRuleID    string
+		wantLevel     string
+		wantMessage   string
+	}{
+		{
+			name: "happy path",
+			detectedVulns: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2019-0000",
+					PkgName:          "foo",
+					InstalledVersion: "1.2.3",
+					FixedVersion:     "3.4.5",
+					Vulnerability: dbTypes.Vulnerability{
+						Title:       "foobar",
+						Description: "baz",
+						Severity:    "HIGH",
+					},
+				},
+			},
+			wantRuleID:  "CVE-2019-0000",
+			wantLevel:   "error",
+			wantMessage: "foobar",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sarifWritten := bytes.Buffer{}
+			inputResults := report.Results{
+				{
+					Target:          "foosarif",
+					Vulnerabilities: tc.detectedVulns,
+				},
+			}
+
+			assert.NoError(t, report.WriteResults("sarif", &sarifWritten, inputResults, "", false), tc.name)
+
+			var sarifDoc map[string]interface{}
+			assert.NoError(t, json.Unmarshal(sarifWritten.Bytes(), &sarifDoc), "invalid sarif json", tc.name)
+			assert.Equal(t, "2.1.0", sarifDoc["version"], tc.name)
+
+			runs := sarifDoc["runs"].([]interface{})
+			assert.Len(t, runs, 1, tc.name)
+			run := runs[0].(map[string]interface{})
+
+			results := run["results"].([]interface{})
+			assert.Len(t, results, 1, tc.name)
+			result := results[0].(map[string]interface{})
+			assert.Equal(t, tc.wantRuleID, result["ruleId"], tc.name)
+			assert.Equal(t, tc.wantLevel, result["level"], tc.name)
+			assert.Equal(t, tc.wantMessage, result["message"].(map[string]interface{})["text"], tc.name)
+		})
+	}
+}
+
 func TestReportWriter_Template(t *testing.T) {
 	testCases := []struct {
 		name          string
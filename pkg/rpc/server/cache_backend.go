@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	fanalCache "github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/fanal/types"
+	"github.com/aquasecurity/trivy/internal/server/config"
+	"github.com/aquasecurity/trivy/pkg/cache"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// newRemoteCacheBackend builds the read-through cache.Backend selected by
+// c.CacheBackend. A "fs" (or empty) backend means every trivy-server
+// instance keeps analyzing layers locally, as before; "redis"/"s3" let a
+// fleet behind a load balancer share layer-analysis results.
+func newRemoteCacheBackend(c config.Config) (cache.Backend, error) {
+	if c.CacheBackend == "" || c.CacheBackend == "fs" {
+		return nil, nil
+	}
+
+	backend, err := cache.New(cache.Options{
+		Backend:       c.CacheBackend,
+		TTL:           c.CacheTTL,
+		RedisAddr:     c.CacheRedisAddr,
+		RedisPassword: c.CacheRedisPass,
+		RedisDB:       c.CacheRedisDB,
+		S3Bucket:      c.CacheS3Bucket,
+		S3Prefix:      c.CacheS3Prefix,
+		S3Region:      c.CacheS3Region,
+	}, c.Metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Logger.Infof("Using %s cache backend for layer analysis sharing", c.CacheBackend)
+	return backend, nil
+}
+
+// withRemoteCache wraps local with remote so layer analysis is actually
+// shared across a fleet of trivy-servers: artifact/blob info is written
+// through to remote alongside the local on-disk cache, and read from remote
+// on a local miss. remote is nil when no --cache-backend is configured, in
+// which case local is returned unchanged.
+func withRemoteCache(local fanalCache.FSCache, remote cache.Backend) fanalCache.FSCache {
+	if remote == nil {
+		return local
+	}
+	return &remoteBackedCache{FSCache: local, remote: remote}
+}
+
+// remoteBackedCache is a read-through decorator around a local
+// fanal/cache.FSCache: artifact/blob puts are mirrored into the remote
+// cache.Backend, and artifact/blob gets fall back to it, and populate the
+// local cache, on a local miss.
+type remoteBackedCache struct {
+	fanalCache.FSCache
+	remote cache.Backend
+}
+
+func (c *remoteBackedCache) PutArtifact(artifactID string, artifactInfo types.ArtifactInfo) error {
+	if err := c.FSCache.PutArtifact(artifactID, artifactInfo); err != nil {
+		return err
+	}
+	return c.putRemote("artifact:"+artifactID, artifactInfo)
+}
+
+func (c *remoteBackedCache) PutBlob(blobID string, blobInfo types.BlobInfo) error {
+	if err := c.FSCache.PutBlob(blobID, blobInfo); err != nil {
+		return err
+	}
+	return c.putRemote("blob:"+blobID, blobInfo)
+}
+
+func (c *remoteBackedCache) GetArtifact(artifactID string) (types.ArtifactInfo, error) {
+	info, err := c.FSCache.GetArtifact(artifactID)
+	if err == nil {
+		return info, nil
+	}
+
+	var remoteInfo types.ArtifactInfo
+	found, getErr := c.getRemote("artifact:"+artifactID, &remoteInfo)
+	if getErr != nil || !found {
+		return types.ArtifactInfo{}, err
+	}
+
+	if putErr := c.FSCache.PutArtifact(artifactID, remoteInfo); putErr != nil {
+		log.Logger.Warnf("failed to populate local cache from remote backend: %s", putErr)
+	}
+	return remoteInfo, nil
+}
+
+func (c *remoteBackedCache) GetBlob(blobID string) (types.BlobInfo, error) {
+	info, err := c.FSCache.GetBlob(blobID)
+	if err == nil {
+		return info, nil
+	}
+
+	var remoteInfo types.BlobInfo
+	found, getErr := c.getRemote("blob:"+blobID, &remoteInfo)
+	if getErr != nil || !found {
+		return types.BlobInfo{}, err
+	}
+
+	if putErr := c.FSCache.PutBlob(blobID, remoteInfo); putErr != nil {
+		log.Logger.Warnf("failed to populate local cache from remote backend: %s", putErr)
+	}
+	return remoteInfo, nil
+}
+
+func (c *remoteBackedCache) putRemote(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal %s for remote cache: %w", key, err)
+	}
+	if err := c.remote.Put(context.Background(), key, data); err != nil {
+		return xerrors.Errorf("failed to write %s to remote cache: %w", key, err)
+	}
+	return nil
+}
+
+func (c *remoteBackedCache) getRemote(key string, out interface{}) (bool, error) {
+	data, found, err := c.remote.Get(context.Background(), key)
+	if err != nil {
+		return false, xerrors.Errorf("failed to read %s from remote cache: %w", key, err)
+	}
+	if !found {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, xerrors.Errorf("failed to unmarshal %s from remote cache: %w", key, err)
+	}
+	return true, nil
+}
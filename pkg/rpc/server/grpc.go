@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/aquasecurity/fanal/cache"
+	"github.com/aquasecurity/trivy/internal/server/auth"
+	"github.com/aquasecurity/trivy/internal/server/config"
+	trivyCache "github.com/aquasecurity/trivy/pkg/cache"
+	"github.com/aquasecurity/trivy/pkg/log"
+	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
+	rpcDetector "github.com/aquasecurity/trivy/rpc/detector"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+// ListenAndServeGRPC starts a native gRPC server alongside the Twirp/HTTP
+// server, exposing the same scan/cache/detector RPCs plus the standard
+// grpc.health.v1.Health service and server reflection, so trivy-server can
+// be consumed from gRPC-native ecosystems.
+func ListenAndServeGRPC(c config.Config, fsCache cache.FSCache, remoteCache trivyCache.Backend, requestWg, dbUpdateWg *sync.WaitGroup, healthSrv *health.Server, authChain *auth.Chain) error {
+	lis, err := net.Listen("tcp", c.GRPCListen)
+	if err != nil {
+		return xerrors.Errorf("failed to listen on %s: %w", c.GRPCListen, err)
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			unaryAuthInterceptor(authChain),
+			unaryWaitGroupInterceptor(dbUpdateWg, requestWg),
+		),
+		grpc.ChainStreamInterceptor(
+			streamAuthInterceptor(authChain),
+			streamWaitGroupInterceptor(dbUpdateWg, requestWg),
+		),
+	)
+
+	rpcScanner.RegisterScannerServer(grpcServer, initializeScanServer(fsCache))
+	rpcCache.RegisterCacheServer(grpcServer, NewCacheServer(withRemoteCache(fsCache, remoteCache)))
+	rpcDetector.RegisterOSDetectorServer(grpcServer, initializeOspkgServer())
+	rpcDetector.RegisterLibDetectorServer(grpcServer, initializeLibServer())
+
+	healthpb.RegisterHealthServer(grpcServer, healthSrv)
+	reflection.Register(grpcServer)
+
+	log.Logger.Infof("Listening gRPC %s...", c.GRPCListen)
+	return grpcServer.Serve(lis)
+}
+
+// unaryAuthInterceptor authenticates unary gRPC calls through the same
+// OIDC/mTLS/static-token auth.Chain used by the Twirp/HTTP handlers, instead
+// of only understanding the legacy static token.
+func unaryAuthInterceptor(chain *auth.Chain) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, err := chain.Authenticate(grpcAuthRequest(ctx), info.FullMethod); err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor is the streaming-RPC equivalent of unaryAuthInterceptor.
+func streamAuthInterceptor(chain *auth.Chain) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, err := chain.Authenticate(grpcAuthRequest(ss.Context()), info.FullMethod); err != nil {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(srv, ss)
+	}
+}
+
+// unaryWaitGroupInterceptor mirrors withWaitGroup's throttling: requests are
+// suspended during a DB hot-swap and counted so the hot-swap can wait for
+// in-flight requests to drain.
+func unaryWaitGroupInterceptor(dbUpdateWg, requestWg *sync.WaitGroup) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		dbUpdateWg.Wait()
+		requestWg.Add(1)
+		defer requestWg.Done()
+		return handler(ctx, req)
+	}
+}
+
+// streamWaitGroupInterceptor is the streaming-RPC equivalent of unaryWaitGroupInterceptor.
+func streamWaitGroupInterceptor(dbUpdateWg, requestWg *sync.WaitGroup) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		dbUpdateWg.Wait()
+		requestWg.Add(1)
+		defer requestWg.Done()
+		return handler(srv, ss)
+	}
+}
+
+// newHealthServer wires the standard gRPC health service to DB-update state:
+// it starts NOT_SERVING until the first successful update attempt and flips
+// back to NOT_SERVING after any failed attempt (see markServing).
+func newHealthServer() *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// markServing is called by the DB worker after every update attempt to keep
+// the gRPC health service's view of readiness in sync with that attempt's
+// outcome. A nil h
+// is a no-op, so callers that don't have one configured (e.g. in tests) can
+// call it unconditionally.
+func markServing(h *health.Server, healthy bool) {
+	if h == nil {
+		return
+	}
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if healthy {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	h.SetServingStatus("", status)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aquasecurity/trivy/internal/server/health"
+	"github.com/aquasecurity/trivy/pkg/vulnsrc"
+)
+
+var (
+	vulnSrcTrackersMu sync.Mutex
+	vulnSrcTrackers   = map[string]*health.Tracker{}
+)
+
+// vulnSrcTracker returns the health.Tracker for the given vulnsrc.Fetcher
+// name, registering one on first use so every fetcher shows up under
+// GET /health even before its first run. Fetchers are non-essential: a
+// stalled third-party source shouldn't fail the aggregated health check.
+func vulnSrcTracker(name string, grace time.Duration) *health.Tracker {
+	vulnSrcTrackersMu.Lock()
+	defer vulnSrcTrackersMu.Unlock()
+
+	t, ok := vulnSrcTrackers[name]
+	if !ok {
+		t = health.NewTracker("vulnsrc:"+name, false, grace)
+		vulnSrcTrackers[name] = t
+		health.Register(t)
+	}
+	return t
+}
+
+// cacheHealthChecker reports whether the on-disk DB cache directory is
+// still present and a directory, satisfying health.Healthchecker.
+type cacheHealthChecker struct {
+	cacheDir string
+}
+
+func (c cacheHealthChecker) Name() string      { return "cache" }
+func (c cacheHealthChecker) IsEssential() bool { return true }
+
+func (c cacheHealthChecker) IsHealthy() bool {
+	info, err := os.Stat(c.cacheDir)
+	return err == nil && info.IsDir()
+}
+
+func (c cacheHealthChecker) Message() string {
+	if c.IsHealthy() {
+		return ""
+	}
+	if _, err := os.Stat(c.cacheDir); err != nil {
+		return err.Error()
+	}
+	return "cache directory path exists but is not a directory"
+}
+
+func (c cacheHealthChecker) LastUpdate() time.Time  { return time.Time{} }
+func (c cacheHealthChecker) LastSuccess() time.Time { return time.Time{} }
+
+// registerHealthchecks wires the db-worker, every registered vulnsrc.Fetcher
+// and the on-disk cache into the internal/server/health registry backing
+// GET /health.
+func registerHealthchecks(worker dbWorker, cacheDir string, grace time.Duration) dbWorker {
+	worker.health = health.NewTracker("db-worker", true, grace)
+	health.Register(worker.health)
+
+	for _, f := range vulnsrc.ListFetchers() {
+		vulnSrcTracker(f.Name(), grace)
+	}
+
+	health.Register(cacheHealthChecker{cacheDir: cacheDir})
+
+	return worker
+}
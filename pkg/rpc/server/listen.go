@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -11,6 +12,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/afero"
+	"go.uber.org/zap"
+	grpchealth "google.golang.org/grpc/health"
 
 	"github.com/google/wire"
 	"github.com/twitchtv/twirp"
@@ -19,9 +22,13 @@ import (
 	"github.com/aquasecurity/fanal/cache"
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy/internal/server/config"
+	"github.com/aquasecurity/trivy/internal/server/health"
+	"github.com/aquasecurity/trivy/internal/server/metrics"
 	dbFile "github.com/aquasecurity/trivy/pkg/db"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/notification"
 	"github.com/aquasecurity/trivy/pkg/utils"
+	"github.com/aquasecurity/trivy/pkg/vulnsrc"
 	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
 	"github.com/aquasecurity/trivy/rpc/detector"
 	rpcDetector "github.com/aquasecurity/trivy/rpc/detector"
@@ -46,51 +53,179 @@ func ListenAndServe(c config.Config, fsCache cache.FSCache) error {
 			requestWg.Add(1)
 			defer requestWg.Done()
 
+			if c.Metrics != nil {
+				c.Metrics.InFlightRequests.Inc()
+				defer c.Metrics.InFlightRequests.Dec()
+			}
+
 			base.ServeHTTP(w, r)
 
 		})
 	}
 
-	go func() {
-		worker := initializeDBWorker(c.CacheDir, true)
-		if err := initializeMetricGauge(c.GaugeMetric, c.CacheDir); err != nil {
-			c.GaugeMetric = nil // just in case some if some error/issue occurs (probably with GaugeMetric). So, making c.GaugeMetric nil and hence, unusable for any of the functions which require it
-			log.Logger.Errorf("%+v\n", err)
-		}
-		ctx := context.Background()
-		for {
-			time.Sleep(1 * time.Hour)
-			if err := worker.update(ctx, c.AppVersion, c.CacheDir, dbUpdateWg, requestWg, c.GaugeMetric); err != nil {
-				log.Logger.Errorf("%+v\n", err)
+	hooks := twirp.ChainHooks(metricsHooks(c.Metrics), loggingHooks())
+
+	withRequestLog := func(base http.Handler) http.Handler {
+		return withRequestLogging(log.Logger, base)
+	}
+
+	worker := initializeDBWorker(c.CacheDir, true)
+	worker.metrics = c.Metrics
+	worker.vulnSrcFetchEnabled = c.VulnSrcFetchEnabled
+	if err := initializeMetricGauge(c.GaugeMetric, c.CacheDir); err != nil {
+		c.GaugeMetric = nil // just in case some if some error/issue occurs (probably with GaugeMetric). So, making c.GaugeMetric nil and hence, unusable for any of the functions which require it
+		log.Logger.Errorf("%+v\n", err)
+	}
+
+	// Built here, before NewScheduler takes its own copy of worker, so that
+	// the scheduler's update path can flip this server's gRPC health status
+	// alongside the db-worker health tracker registered below. Reused as-is by the
+	// ListenAndServeGRPC goroutine further down instead of constructing a
+	// second, disconnected health.Server.
+	grpcHealth := newHealthServer()
+	worker.grpcHealth = grpcHealth
+
+	dbUpdateInterval := c.DBUpdateInterval
+	if dbUpdateInterval <= 0 {
+		dbUpdateInterval = 1 * time.Hour
+	}
+	worker = registerHealthchecks(worker, c.CacheDir, 2*dbUpdateInterval)
+	registerNotifiers(c)
+	scheduler := NewScheduler(worker, c.AppVersion, c.CacheDir, dbUpdateInterval, c.Metrics)
+
+	schedulerCtx, cancelScheduler := context.WithCancel(context.Background())
+	defer cancelScheduler()
+	go scheduler.Run(schedulerCtx, dbUpdateWg, requestWg, c.GaugeMetric)
+
+	remoteCache, err := newRemoteCacheBackend(c)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize cache backend: %w", err)
+	}
+	if remoteCache != nil {
+		defer remoteCache.Close()
+	}
+
+	authChain, err := newAuthChain(context.Background(), c)
+	if err != nil {
+		return xerrors.Errorf("failed to initialize auth chain: %w", err)
+	}
+
+	if c.GRPCListen != "" {
+		go func() {
+			if err := ListenAndServeGRPC(c, fsCache, remoteCache, requestWg, dbUpdateWg, grpcHealth, authChain); err != nil {
+				log.Logger.Errorf("gRPC server error: %+v", err)
 			}
-		}
-	}()
+		}()
+	}
 
 	mux := http.NewServeMux()
 
-	scanHandler := rpcScanner.NewScannerServer(initializeScanServer(fsCache), nil)
-	mux.Handle(rpcScanner.ScannerPathPrefix, withToken(withWaitGroup(scanHandler), c.Token, c.TokenHeader))
+	scanHandler := rpcScanner.NewScannerServer(initializeScanServer(fsCache), hooks)
+	mux.Handle(rpcScanner.ScannerPathPrefix, withAuth(withWaitGroup(withRequestLog(scanHandler)), authChain, c.Metrics, "Scanner.*"))
 
-	layerHandler := rpcCache.NewCacheServer(NewCacheServer(fsCache), nil)
-	mux.Handle(rpcCache.CachePathPrefix, withToken(withWaitGroup(layerHandler), c.Token, c.TokenHeader))
+	layerHandler := rpcCache.NewCacheServer(NewCacheServer(withRemoteCache(fsCache, remoteCache)), hooks)
+	mux.Handle(rpcCache.CachePathPrefix, withAuth(withWaitGroup(withRequestLog(layerHandler)), authChain, c.Metrics, "Cache.*"))
 
 	// osHandler is for backward compatibility
-	osHandler := rpcDetector.NewOSDetectorServer(initializeOspkgServer(), nil)
-	mux.Handle(rpcDetector.OSDetectorPathPrefix, withToken(withWaitGroup(osHandler), c.Token, c.TokenHeader))
+	osHandler := rpcDetector.NewOSDetectorServer(initializeOspkgServer(), hooks)
+	mux.Handle(rpcDetector.OSDetectorPathPrefix, withAuth(withWaitGroup(withRequestLog(osHandler)), authChain, c.Metrics, "OSDetector.*"))
 
 	// libHandler is for backward compatibility
-	libHandler := rpcDetector.NewLibDetectorServer(initializeLibServer(), nil)
-	mux.Handle(rpcDetector.LibDetectorPathPrefix, withToken(withWaitGroup(libHandler), c.Token, c.TokenHeader))
+	libHandler := rpcDetector.NewLibDetectorServer(initializeLibServer(), hooks)
+	mux.Handle(rpcDetector.LibDetectorPathPrefix, withAuth(withWaitGroup(withRequestLog(libHandler)), authChain, c.Metrics, "LibDetector.*"))
 
 	// promHandler is for dealing with update the custom prometheus metrics
 	promHandler := promhttp.HandlerFor(c.MetricsRegistry, promhttp.HandlerOpts{Timeout: 10 * time.Second})
-	mux.Handle("/metrics", withToken(withWaitGroup(promHandler), c.Token, c.TokenHeader))
+	mux.Handle("/metrics", withAuth(withWaitGroup(promHandler), authChain, c.Metrics, "metrics"))
+
+	// healthz/readyz let operators wire k8s liveness/readiness probes to DB freshness
+	mux.Handle("/healthz", handleHealthz())
+	mux.Handle("/readyz", handleReadyz(c.CacheDir))
+
+	// /health reports readiness plus per-subsystem detail (db-worker, every
+	// vulnsrc.Fetcher, the on-disk cache), failing as soon as any essential
+	// subsystem goes unhealthy.
+	mux.Handle("/health", health.Handler())
+
+	// /api/v1/db/refresh lets operators run an out-of-band DB update attempt
+	refreshHandler := handleDBRefresh(scheduler, dbUpdateWg, requestWg, c.GaugeMetric, c.CacheDir)
+	mux.Handle("/api/v1/db/refresh", withAuth(refreshHandler, authChain, c.Metrics, "db-refresh"))
 
 	log.Logger.Infof("Listening %s...", c.Listen)
 
 	return http.ListenAndServe(c.Listen, mux)
 }
 
+// metricsHooks wraps every twirp RPC call with request-count and latency
+// observations, labeled by service, method and status code.
+func metricsHooks(m *metrics.Metrics) *twirp.ServerHooks {
+	type startTimeKey struct{}
+
+	return &twirp.ServerHooks{
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			return context.WithValue(ctx, startTimeKey{}, time.Now()), nil
+		},
+		ResponseSent: func(ctx context.Context) {
+			if m == nil {
+				return
+			}
+			service, _ := twirp.ServiceName(ctx)
+			method, _ := twirp.MethodName(ctx)
+			code, _ := twirp.StatusCode(ctx)
+
+			var duration time.Duration
+			if start, ok := ctx.Value(startTimeKey{}).(time.Time); ok {
+				duration = time.Since(start)
+			}
+			m.ObserveRPC(service, method, code, duration)
+		},
+	}
+}
+
+// handleHealthz reports liveness: the process is up and serving.
+func handleHealthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}
+}
+
+// readyzStatus is the JSON body returned by /readyz.
+type readyzStatus struct {
+	Status     string    `json:"status"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+	NextUpdate time.Time `json:"next_update,omitempty"`
+}
+
+// handleReadyz reports readiness based on how fresh the local vulnerability
+// DB is, so a trivy-server stuck failing updates can be pulled out of rotation.
+func handleReadyz(cacheDir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m := dbFile.NewMetadata(afero.NewOsFs(), cacheDir)
+		metadata, err := m.Get()
+
+		status := readyzStatus{Status: "ok"}
+		code := http.StatusOK
+		switch {
+		case err != nil:
+			status.Status = xerrors.Errorf("failed to read db metadata: %w", err).Error()
+			code = http.StatusServiceUnavailable
+		case time.Now().After(metadata.NextUpdate.Add(2 * time.Hour)):
+			status.Status = "stale"
+			status.UpdatedAt = metadata.UpdatedAt
+			status.NextUpdate = metadata.NextUpdate
+			code = http.StatusServiceUnavailable
+		default:
+			status.UpdatedAt = metadata.UpdatedAt
+			status.NextUpdate = metadata.NextUpdate
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(status)
+	}
+}
+
 func withToken(base http.Handler, token, tokenHeader string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if token != "" && token != r.Header.Get(tokenHeader) {
@@ -103,6 +238,24 @@ func withToken(base http.Handler, token, tokenHeader string) http.Handler {
 
 type dbWorker struct {
 	dbClient dbFile.Operation
+
+	// metrics is optional and, when set, lets hotUpdate record per-source
+	// vulnsrc.Fetcher timestamps alongside the regular DB update metrics.
+	metrics *metrics.Metrics
+
+	// health is optional and, when set, backs the "db-worker" subsystem
+	// reported by GET /health.
+	health *health.Tracker
+
+	// grpcHealth is optional and, when set, keeps the grpc.health.v1.Health
+	// service exposed by ListenAndServeGRPC in sync with DB-update state.
+	grpcHealth *grpchealth.Server
+
+	// vulnSrcFetchEnabled gates whether hotUpdate fetches registered
+	// vulnsrc.Fetcher sources at all. It defaults to false (the zero
+	// value), so a bare dbWorker{} built by a test never makes outbound
+	// HTTPS calls to third-party feeds.
+	vulnSrcFetchEnabled bool
 }
 
 func newDBWorker(dbClient dbFile.Operation) dbWorker {
@@ -111,27 +264,64 @@ func newDBWorker(dbClient dbFile.Operation) dbWorker {
 
 func (w dbWorker) update(ctx context.Context, appVersion, cacheDir string,
 	dbUpdateWg, requestWg *sync.WaitGroup, gaugeMetric *prometheus.GaugeVec) error {
+	w.health.Attempt()
 	if err := updateLastDBUpdatePrometheus(gaugeMetric, float64(time.Now().Unix()), true); err != nil { //updating the last_db_update_attempt prom metric coz a db update attempt got triggered
+		w.health.Fail(err)
+		markServing(w.grpcHealth, false)
 		return xerrors.Errorf("%w", err)
 	}
 	log.Logger.Debug("Check for DB update...")
 	needsUpdate, err := w.dbClient.NeedsUpdate(appVersion, false, false)
 	if err != nil {
+		w.health.Fail(err)
+		markServing(w.grpcHealth, false)
 		return xerrors.Errorf("failed to check if db needs an update")
 	} else if !needsUpdate {
+		w.health.Succeed()
+		markServing(w.grpcHealth, true)
 		return nil
 	}
 
-	log.Logger.Info("Updating DB...")
+	log.With(zap.String("app_version", appVersion)).Info("Updating DB...")
 	if err = w.hotUpdate(ctx, cacheDir, dbUpdateWg, requestWg); err != nil {
+		w.health.Fail(err)
+		markServing(w.grpcHealth, false)
 		return xerrors.Errorf("failed DB hot update")
 	}
 	if err = updateLastDBUpdatePrometheus(gaugeMetric, float64(time.Now().Unix()), false); err != nil { //updating the last_db_update metric (here, coz code reaches till here, then it's a successful db update)
+		w.health.Fail(err)
+		markServing(w.grpcHealth, false)
 		return xerrors.Errorf("%w", err)
 	}
+	w.health.Succeed()
+	markServing(w.grpcHealth, true)
+	w.notifyUpdate(ctx, cacheDir)
 	return nil
 }
 
+// notifyUpdate broadcasts a notification.Notification to every configured
+// notification.Notifier after a successful update.
+//
+// INCOMPLETE: this only ever reports that an update happened, not what
+// changed in it. New/Removed/Changed are left as the empty slices documented
+// on notification.Notification, since trivy-db doesn't expose a way to diff
+// the new DB content against the previous snapshot through the APIs used
+// here. DBVersion is derived from the refreshed metadata's update timestamp
+// instead of a real content/schema version, for the same reason.
+
+func (w dbWorker) notifyUpdate(ctx context.Context, cacheDir string) {
+	metadata, err := dbFile.NewMetadata(afero.NewOsFs(), cacheDir).Get()
+	if err != nil {
+		log.With(zap.Error(err)).Warn("failed to read db metadata for notification")
+		return
+	}
+
+	notification.Broadcast(ctx, notification.Notification{
+		DBVersion: metadata.UpdatedAt.Format(time.RFC3339),
+		Timestamp: time.Now(),
+	})
+}
+
 func (w dbWorker) hotUpdate(ctx context.Context, cacheDir string, dbUpdateWg, requestWg *sync.WaitGroup) error {
 	tmpDir, err := ioutil.TempDir("", "db")
 	if err != nil {
@@ -140,6 +330,7 @@ func (w dbWorker) hotUpdate(ctx context.Context, cacheDir string, dbUpdateWg, re
 	defer os.RemoveAll(tmpDir)
 
 	if err := w.dbClient.Download(ctx, tmpDir, false); err != nil {
+		log.With(zap.Error(err)).Error("failed to download vulnerability DB")
 		return xerrors.Errorf("failed to download vulnerability DB: %w", err)
 	}
 
@@ -167,9 +358,71 @@ func (w dbWorker) hotUpdate(ctx context.Context, cacheDir string, dbUpdateWg, re
 		return xerrors.Errorf("failed to open DB: %w", err)
 	}
 
+	// Refresh any additional vulnsrc.Fetcher sources after each trivy-db
+	// hot update, in a separate goroutine off the critical hot-swap path. A
+	// fetcher failure is logged but never fails the hot update. Gated behind
+	// vulnSrcFetchEnabled since these fetchers make live outbound HTTPS
+	// calls to third-party feeds, which tests and airgapped deployments
+	// must be able to opt out of.
+	if w.vulnSrcFetchEnabled {
+		go w.updateVulnSources(ctx, cacheDir)
+	}
+
 	return nil
 }
 
+// updateVulnSources runs every registered vulnsrc.Fetcher, merges its
+// output into the source's supplementary local store, and records a
+// per-source timestamp for each attempt/success. Fetcher errors, and
+// failures to merge a fetcher's output, are logged and otherwise ignored.
+//
+// Each source's new result is diffed against its previous snapshot (via
+// vulnsrc.LoadLocalStore/Diff) before the snapshot is overwritten, and the
+// outcome is broadcast as its own notification.Notification, so
+// notification.Notifiers learn which vulnerabilities are actually new,
+// removed or changed for that source instead of only "an update happened".
+func (w dbWorker) updateVulnSources(ctx context.Context, cacheDir string) {
+	for _, f := range vulnsrc.ListFetchers() {
+		tracker := vulnSrcTracker(f.Name(), 2*time.Hour)
+		tracker.Attempt()
+		w.metrics.ObserveVulnSource(f.Name(), "attempt")
+
+		fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		resp, err := f.Fetch(fetchCtx, cacheDir)
+		cancel()
+		if err != nil {
+			tracker.Fail(err)
+			log.With(zap.String("source", f.Name()), zap.Error(err)).Warn("vulnsrc fetcher failed")
+			continue
+		}
+
+		prev, err := vulnsrc.LoadLocalStore(cacheDir, f.Name())
+		if err != nil {
+			log.With(zap.String("source", f.Name()), zap.Error(err)).Warn("failed to load previous vulnsrc snapshot for diffing")
+		}
+
+		if err := vulnsrc.MergeIntoLocalStore(cacheDir, f.Name(), resp.Vulnerabilities); err != nil {
+			tracker.Fail(err)
+			log.With(zap.String("source", f.Name()), zap.Error(err)).Warn("failed to merge vulnsrc fetcher output into local store")
+			continue
+		}
+
+		tracker.Succeed()
+		w.metrics.ObserveVulnSource(f.Name(), "success")
+		log.With(zap.String("source", f.Name()), zap.Int("vulnerabilities", len(resp.Vulnerabilities))).
+			Info("merged vulnsrc fetcher output into local store")
+
+		added, removed, changed := vulnsrc.Diff(prev, resp.Vulnerabilities)
+		notification.Broadcast(ctx, notification.Notification{
+			New:       added,
+			Removed:   removed,
+			Changed:   changed,
+			DBVersion: f.Name(),
+			Timestamp: time.Now(),
+		})
+	}
+}
+
 func initializeMetricGauge(gauge *prometheus.GaugeVec, cacheDir string) error {
 	// the nil gauge situation will be caught by updateLastDBUpdatePrometheus function
 	m := dbFile.NewMetadata(afero.NewOsFs(), cacheDir)
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/twitchtv/twirp"
+	"go.uber.org/zap"
+)
+
+type loggerCtxKey struct{}
+
+// requestLogCtxKey is the context key under which withRequestLogging stores
+// a *requestLogInfo for loggingHooks to fill in.
+type requestLogCtxKey struct{}
+
+// requestLogInfo carries the twirp service/method resolved for a request,
+// written by loggingHooks and read back by withRequestLogging once the
+// request completes.
+type requestLogInfo struct {
+	service string
+	method  string
+}
+
+// loggingHooks captures the twirp service/method twirp resolves for a
+// request into whatever *requestLogInfo withRequestLogging stored in ctx.
+// Twirp's generated ServeHTTP derives its ctx from the *http.Request it was
+// called with but never mutates that request in place (it reassigns a local
+// variable), so reading twirp.ServiceName/MethodName back from the request
+// after ServeHTTP returns, as withRequestLogging used to, always sees an
+// empty value; the hooks run with twirp's real derived ctx and can reach
+// the same *requestLogInfo pointer through the ancestor context value.
+func loggingHooks() *twirp.ServerHooks {
+	return &twirp.ServerHooks{
+		ResponseSent: func(ctx context.Context) {
+			info, ok := ctx.Value(requestLogCtxKey{}).(*requestLogInfo)
+			if !ok {
+				return
+			}
+			info.service, _ = twirp.ServiceName(ctx)
+			info.method, _ = twirp.MethodName(ctx)
+		},
+	}
+}
+
+// loggerFromContext returns the request-scoped logger injected by
+// withRequestLogging, falling back to fallback if none was injected.
+func loggerFromContext(ctx context.Context, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(*zap.SugaredLogger); ok {
+		return l
+	}
+	return fallback
+}
+
+// withRequestLogging propagates the caller's X-Request-Id (generating one if
+// absent) and echoes it on the response, injects a request-scoped logger
+// carrying request_id, traceparent (as received, empty if absent) and
+// remote_addr into the context, and emits one structured start/finish log
+// line per request. The finish line also carries rpc_service/rpc_method,
+// populated by loggingHooks, which must be chained into the twirp server's
+// hooks.
+func withRequestLogging(logger *zap.SugaredLogger, base http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-Id")
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		w.Header().Set("X-Request-Id", requestID)
+
+		traceparent := r.Header.Get("traceparent")
+
+		reqLogger := logger.With(
+			"request_id", requestID,
+			"traceparent", traceparent,
+			"remote_addr", r.RemoteAddr,
+		)
+
+		info := &requestLogInfo{}
+		ctx := context.WithValue(r.Context(), loggerCtxKey{}, reqLogger)
+		ctx = context.WithValue(ctx, requestLogCtxKey{}, info)
+		r = r.WithContext(ctx)
+
+		start := time.Now()
+		reqLogger.Infow("rpc request started", "path", r.URL.Path)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		base.ServeHTTP(rec, r)
+
+		reqLogger.Infow("rpc request finished",
+			"rpc_service", info.service,
+			"rpc_method", info.method,
+			"status", rec.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	})
+}
+
+// statusRecorder captures the HTTP status code written by the wrapped
+// handler so it can be logged after the response is sent.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
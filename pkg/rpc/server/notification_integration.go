@@ -0,0 +1,24 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/aquasecurity/trivy/internal/server/config"
+	"github.com/aquasecurity/trivy/pkg/notification"
+)
+
+var notifiersOnce sync.Once
+
+// registerNotifiers wires the webhook/file notifiers configured via CLI
+// flags into the global notification registry. It is safe to call more
+// than once; only the first call takes effect.
+func registerNotifiers(c config.Config) {
+	notifiersOnce.Do(func() {
+		if c.NotificationWebhookURL != "" {
+			notification.RegisterNotifier("webhook", notification.NewWebhookNotifier(c.NotificationWebhookURL, c.NotificationWebhookSecret))
+		}
+		if c.NotificationFilePath != "" {
+			notification.RegisterNotifier("file", notification.NewFileNotifier(c.NotificationFilePath))
+		}
+	})
+}
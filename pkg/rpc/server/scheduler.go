@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/internal/server/metrics"
+	dbFile "github.com/aquasecurity/trivy/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// Scheduler drives the periodic DB update loop. Unlike a bare
+// time.Sleep(interval) loop, it backs off with full jitter on repeated
+// failures so that a fleet of trivy-servers restarted together doesn't
+// stampede the DB origin, and it exposes a way to force an update on demand.
+type Scheduler struct {
+	worker     dbWorker
+	appVersion string
+	cacheDir   string
+	metrics    *metrics.Metrics
+
+	baseInterval time.Duration
+	maxInterval  time.Duration
+
+	mu       sync.Mutex // guards updating/attempt; in addition to the existing dbUpdateWg
+	updating bool
+	attempt  int
+}
+
+// NewScheduler returns a Scheduler with the given base interval and a
+// backoff cap of 6x the base interval.
+func NewScheduler(worker dbWorker, appVersion, cacheDir string, baseInterval time.Duration, m *metrics.Metrics) *Scheduler {
+	return &Scheduler{
+		worker:       worker,
+		appVersion:   appVersion,
+		cacheDir:     cacheDir,
+		metrics:      m,
+		baseInterval: baseInterval,
+		maxInterval:  6 * baseInterval,
+	}
+}
+
+// Run blocks, triggering updates on the scheduled interval until ctx is
+// canceled.
+func (s *Scheduler) Run(ctx context.Context, dbUpdateWg, requestWg *sync.WaitGroup, gauge *prometheus.GaugeVec) {
+	for {
+		wait := s.nextWait()
+		s.setNextScheduled(wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if err := s.Trigger(ctx, dbUpdateWg, requestWg, gauge); err != nil {
+			log.With(zap.String("next_update", s.nextWait().String()), zap.Error(err)).Error("scheduled DB update failed")
+		}
+	}
+}
+
+// Trigger runs a single update attempt now, guarded so only one update can
+// be in flight at a time. It is safe to call concurrently, e.g. from both
+// the scheduled loop and the manual refresh endpoint.
+func (s *Scheduler) Trigger(ctx context.Context, dbUpdateWg, requestWg *sync.WaitGroup, gauge *prometheus.GaugeVec) error {
+	s.mu.Lock()
+	if s.updating {
+		s.mu.Unlock()
+		return xerrors.New("a DB update is already in progress")
+	}
+	s.updating = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.updating = false
+		s.mu.Unlock()
+	}()
+
+	start := time.Now()
+	err := s.worker.update(ctx, s.appVersion, s.cacheDir, dbUpdateWg, requestWg, gauge)
+	s.metrics.ObserveDBUpdate(time.Since(start), err)
+
+	s.mu.Lock()
+	if err != nil {
+		s.attempt++
+	} else {
+		s.attempt = 0
+	}
+	s.mu.Unlock()
+	s.setBackoffGauge()
+
+	if err != nil {
+		return xerrors.Errorf("scheduled DB update failed: %w", err)
+	}
+	return nil
+}
+
+// nextWait returns the base interval on a healthy schedule, or a
+// full-jitter exponential backoff (capped at maxInterval) after failures.
+func (s *Scheduler) nextWait() time.Duration {
+	s.mu.Lock()
+	attempt := s.attempt
+	s.mu.Unlock()
+
+	if attempt == 0 {
+		return s.baseInterval
+	}
+
+	backoff := time.Duration(float64(s.baseInterval) * math.Pow(2, float64(attempt)))
+	if backoff > s.maxInterval {
+		backoff = s.maxInterval
+	}
+	// Full jitter: a uniformly random wait between 0 and backoff.
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
+
+func (s *Scheduler) setNextScheduled(wait time.Duration) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.DBUpdateNextScheduled.Set(float64(time.Now().Add(wait).Unix()))
+}
+
+func (s *Scheduler) setBackoffGauge() {
+	if s.metrics == nil {
+		return
+	}
+	s.mu.Lock()
+	attempt := s.attempt
+	s.mu.Unlock()
+
+	backoff := time.Duration(0)
+	if attempt > 0 {
+		backoff = time.Duration(float64(s.baseInterval) * math.Pow(2, float64(attempt)))
+		if backoff > s.maxInterval {
+			backoff = s.maxInterval
+		}
+	}
+	s.metrics.DBUpdateBackoff.Set(backoff.Seconds())
+}
+
+// handleDBRefresh serves POST /api/v1/db/refresh, running an immediate DB
+// update attempt and returning the resulting metadata as JSON. Like a
+// scheduled attempt, it downloads a new DB only when NeedsUpdate reports one
+// is due; otherwise the current metadata is returned unchanged.
+func handleDBRefresh(scheduler *Scheduler, dbUpdateWg, requestWg *sync.WaitGroup, gauge *prometheus.GaugeVec, cacheDir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := scheduler.Trigger(r.Context(), dbUpdateWg, requestWg, gauge); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		metadata, err := dbFile.NewMetadata(afero.NewOsFs(), cacheDir).Get()
+		if err != nil {
+			http.Error(w, xerrors.Errorf("failed to read db metadata: %w", err).Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(metadata)
+	}
+}
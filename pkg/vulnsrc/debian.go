@@ -0,0 +1,107 @@
+package vulnsrc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const debianSecurityTrackerURL = "https://security-tracker.debian.org/tracker/data/json"
+
+// debianFetcher pulls the Debian security-tracker's combined JSON feed.
+type debianFetcher struct {
+	url string
+}
+
+func init() {
+	RegisterFetcher("debian-security-tracker", &debianFetcher{url: debianSecurityTrackerURL})
+}
+
+func (f *debianFetcher) Name() string {
+	return "debian-security-tracker"
+}
+
+// debianRelease is a single "releases" entry for a package/CVE pair in the
+// security-tracker feed.
+type debianRelease struct {
+	Status       string `json:"status"`
+	FixedVersion string `json:"fixed_version"`
+	Urgency      string `json:"urgency"`
+}
+
+// debianEntry is a single CVE entry under a package in the feed.
+type debianEntry struct {
+	Description string                   `json:"description"`
+	Releases    map[string]debianRelease `json:"releases"`
+}
+
+func (f *debianFetcher) Fetch(ctx context.Context, workDir string) (FetcherResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return FetcherResponse{}, xerrors.Errorf("failed to build request for %s: %w", f.url, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return FetcherResponse{}, xerrors.Errorf("failed to fetch debian security-tracker feed from %s: %w", f.url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return FetcherResponse{}, xerrors.Errorf("failed to read debian security-tracker response: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(workDir, "debian-security-tracker.json"), body, 0644); err != nil {
+		return FetcherResponse{}, xerrors.Errorf("failed to persist debian security-tracker feed to %s: %w", workDir, err)
+	}
+
+	vulns, err := parseDebianFeed(body)
+	if err != nil {
+		return FetcherResponse{}, xerrors.Errorf("failed to parse debian security-tracker feed: %w", err)
+	}
+
+	return FetcherResponse{Vulnerabilities: vulns}, nil
+}
+
+// parseDebianFeed decodes the security-tracker's
+// "<package>: <CVE>: {description, releases}" structure into one
+// DetectedVulnerability per package/CVE pair, carrying the fixed version
+// from a release reported as resolved. Releases are read from a map, so
+// when several are resolved, which one's version is used is not defined.
+func parseDebianFeed(body []byte) ([]types.DetectedVulnerability, error) {
+	var feed map[string]map[string]debianEntry
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal feed: %w", err)
+	}
+
+	var vulns []types.DetectedVulnerability
+	for pkgName, cves := range feed {
+		for cveID, entry := range cves {
+			var fixedVersion string
+			for _, release := range entry.Releases {
+				if release.Status == "resolved" && release.FixedVersion != "" && release.FixedVersion != "0" {
+					fixedVersion = release.FixedVersion
+					break
+				}
+			}
+
+			vulns = append(vulns, types.DetectedVulnerability{
+				VulnerabilityID: cveID,
+				PkgName:         pkgName,
+				FixedVersion:    fixedVersion,
+				Vulnerability: dbTypes.Vulnerability{
+					Description: entry.Description,
+				},
+			})
+		}
+	}
+	return vulns, nil
+}
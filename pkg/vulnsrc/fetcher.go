@@ -0,0 +1,70 @@
+// Package vulnsrc lets third parties contribute additional vulnerability
+// feeds to trivy-server without forking it, modeled on Clair's fetcher
+// registry: a Fetcher registers itself under a name, and the DB worker
+// iterates every registered Fetcher on each update cycle, merging its
+// output alongside the trivy-db feed.
+package vulnsrc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// FetcherResponse is the result of a single Fetch call.
+type FetcherResponse struct {
+	Vulnerabilities []types.DetectedVulnerability
+
+	// FlagName/FlagValue hold an incremental-update cursor (e.g. the
+	// last-seen advisory ID or ETag). Both are empty when the source doesn't
+	// support incremental fetches. Neither bundled fetcher sets them, and the
+	// DB worker neither persists them nor passes them back to Fetch.
+	FlagName  string
+	FlagValue string
+
+	// Notes carries free-form diagnostic information. The DB worker does
+	// not read it; only Vulnerabilities is consumed.
+	Notes []string
+}
+
+// Fetcher downloads and parses vulnerability data from a single source.
+type Fetcher interface {
+	// Name identifies the source, e.g. "alpine-secdb" or "debian-security-tracker".
+	// It is used as the "source" label on the last-update/last-success metrics
+	// and must be stable across releases.
+	Name() string
+	// Fetch downloads and parses the source's feed into workDir, returning
+	// the vulnerabilities found plus an optional incremental-update cursor.
+	Fetch(ctx context.Context, workDir string) (FetcherResponse, error)
+}
+
+var (
+	fetchersMu sync.Mutex
+	fetchers   = map[string]Fetcher{}
+)
+
+// RegisterFetcher adds f to the registry under name. It panics if name is
+// already registered, following the same fail-fast convention as database/sql
+// driver registration.
+func RegisterFetcher(name string, f Fetcher) {
+	fetchersMu.Lock()
+	defer fetchersMu.Unlock()
+
+	if _, exists := fetchers[name]; exists {
+		panic("vulnsrc: RegisterFetcher called twice for " + name)
+	}
+	fetchers[name] = f
+}
+
+// ListFetchers returns every registered Fetcher, in no particular order.
+func ListFetchers() []Fetcher {
+	fetchersMu.Lock()
+	defer fetchersMu.Unlock()
+
+	list := make([]Fetcher, 0, len(fetchers))
+	for _, f := range fetchers {
+		list = append(list, f)
+	}
+	return list
+}
@@ -0,0 +1,102 @@
+package vulnsrc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// storeDir is the subdirectory of the trivy-db cache dir that holds each
+// Fetcher's parsed output.
+const storeDir = "vulnsrc"
+
+func storePath(cacheDir, source string) string {
+	return filepath.Join(cacheDir, storeDir, source+".json")
+}
+
+// LoadLocalStore reads back the snapshot a previous MergeIntoLocalStore call
+// wrote for source, so a caller can diff it against a freshly fetched
+// result. A snapshot that hasn't been written yet (first run for source) is
+// not an error: it returns a nil slice.
+func LoadLocalStore(cacheDir, source string) ([]types.DetectedVulnerability, error) {
+	data, err := ioutil.ReadFile(storePath(cacheDir, source))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("failed to read %s: %w", storePath(cacheDir, source), err)
+	}
+
+	var vulns []types.DetectedVulnerability
+	if err := json.Unmarshal(data, &vulns); err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal %s: %w", storePath(cacheDir, source), err)
+	}
+	return vulns, nil
+}
+
+// MergeIntoLocalStore persists vulns as source's supplementary feed
+// alongside the bundled trivy-db artifact in cacheDir. trivy-db does not
+// expose a write API usable from here, so this does not merge into the
+// trivy-db bolt store itself; it writes cacheDir/vulnsrc/<source>.json,
+// which a future scan-time lookup can consult in addition to trivy-db.
+func MergeIntoLocalStore(cacheDir, source string, vulns []types.DetectedVulnerability) error {
+	dir := filepath.Join(cacheDir, storeDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return xerrors.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	data, err := json.Marshal(vulns)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal vulnerabilities for %s: %w", source, err)
+	}
+
+	if err := ioutil.WriteFile(storePath(cacheDir, source), data, 0644); err != nil {
+		return xerrors.Errorf("failed to write %s: %w", storePath(cacheDir, source), err)
+	}
+	return nil
+}
+
+// vulnKey identifies the same reported vulnerability across two snapshots,
+// so Diff can tell "still present, but changed" apart from "new"/"removed".
+func vulnKey(v types.DetectedVulnerability) string {
+	return v.VulnerabilityID + "/" + v.PkgName
+}
+
+// Diff compares a source's previous snapshot (oldVulns, as returned by
+// LoadLocalStore) against its freshly fetched result (newVulns), keyed by
+// vulnerability ID and package name. A vulnerability present in both with a
+// different FixedVersion or InstalledVersion counts as changed rather than
+// untouched.
+func Diff(oldVulns, newVulns []types.DetectedVulnerability) (added, removed, changed []types.DetectedVulnerability) {
+	oldByKey := make(map[string]types.DetectedVulnerability, len(oldVulns))
+	for _, v := range oldVulns {
+		oldByKey[vulnKey(v)] = v
+	}
+
+	seen := make(map[string]struct{}, len(newVulns))
+	for _, v := range newVulns {
+		key := vulnKey(v)
+		seen[key] = struct{}{}
+
+		old, ok := oldByKey[key]
+		if !ok {
+			added = append(added, v)
+			continue
+		}
+		if old.FixedVersion != v.FixedVersion || old.InstalledVersion != v.InstalledVersion {
+			changed = append(changed, v)
+		}
+	}
+
+	for key, v := range oldByKey {
+		if _, ok := seen[key]; !ok {
+			removed = append(removed, v)
+		}
+	}
+
+	return added, removed, changed
+}